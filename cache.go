@@ -1,9 +1,16 @@
 package sample1
 
 import (
+	"container/list"
+	"context"
+	"encoding/gob"
 	"fmt"
+	"os"
+	"sort"
 	"sync"
 	"time"
+
+	"golang.org/x/sync/errgroup"
 )
 
 // PriceService is a service that we can use to get prices for the items
@@ -14,8 +21,16 @@ type PriceService interface {
 
 // TransparentCachePrice is a special price that stores the value and the time it was assigned
 type TransparentCachePrice struct {
+	Value         float64
+	LastAssigment time.Time
+}
+
+// inflightCall represents an in-progress call to the actual price service for a given itemCode
+// Callers that arrive while a call is in flight wait on it instead of starting a new one
+type inflightCall struct {
+	wg    sync.WaitGroup
 	value float64
-	lastAssigment time.Time
+	err   error
 }
 
 // TransparentCache is a cache that wraps the actual service
@@ -24,67 +39,351 @@ type TransparentCachePrice struct {
 type TransparentCache struct {
 	actualPriceService PriceService
 	maxAge             time.Duration
-	prices             map[string]TransparentCachePrice
+	store              Store
+	inflight           map[string]*inflightCall
 	pricesMutex        sync.Mutex
+
+	// maxEntries bounds the number of entries kept in store; 0 means unbounded.
+	// lruList/lruElems track usage order so the least recently used entry is evicted first.
+	maxEntries int
+	lruList    *list.List
+	lruElems   map[string]*list.Element
+	// OnEvict, if set, is called whenever an entry is evicted to make room for a new one.
+	OnEvict func(itemCode string, price float64)
+
+	// done signals the background janitor goroutine to exit; nil if no janitor was started.
+	done      chan struct{}
+	closeOnce sync.Once
+
+	// maxConcurrency bounds how many upstream calls GetPricesFor issues at once; 0 means unbounded.
+	maxConcurrency int
 }
 
-func NewTransparentCache(actualPriceService PriceService, maxAge time.Duration) *TransparentCache {
-	return &TransparentCache{
+// Option configures a TransparentCache at construction time.
+type Option func(*TransparentCache)
+
+// WithMaxConcurrency bounds the number of concurrent calls to actualPriceService that
+// GetPricesFor will issue at once. n <= 0 means unbounded.
+func WithMaxConcurrency(n int) Option {
+	return func(c *TransparentCache) {
+		c.maxConcurrency = n
+	}
+}
+
+func NewTransparentCache(actualPriceService PriceService, maxAge time.Duration, opts ...Option) *TransparentCache {
+	c := &TransparentCache{
 		actualPriceService: actualPriceService,
 		maxAge:             maxAge,
-		prices:             map[string]TransparentCachePrice{},
+		store:              newInMemoryStore(),
+		inflight:           map[string]*inflightCall{},
 		pricesMutex:        sync.Mutex{},
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// NewTransparentCacheWithStore is like NewTransparentCache but lets the caller supply the
+// backing Store, e.g. a RedisStore so the cache can be shared across processes instead of being
+// confined to this process's memory.
+func NewTransparentCacheWithStore(actualPriceService PriceService, maxAge time.Duration, store Store) *TransparentCache {
+	c := NewTransparentCache(actualPriceService, maxAge)
+	c.store = store
+	return c
+}
+
+// NewTransparentCacheWithCapacity is like NewTransparentCache but additionally evicts the least
+// recently used entry whenever inserting a new one would make the store grow past maxEntries.
+func NewTransparentCacheWithCapacity(actualPriceService PriceService, maxAge time.Duration, maxEntries int) *TransparentCache {
+	c := NewTransparentCache(actualPriceService, maxAge)
+	c.maxEntries = maxEntries
+	c.lruList = list.New()
+	c.lruElems = map[string]*list.Element{}
+	return c
+}
+
+// NewTransparentCacheWithJanitor is like NewTransparentCache but also starts a background
+// goroutine that periodically sweeps the cache and removes entries older than maxAge, so that
+// items which are never re-queried don't linger in memory until they happen to be asked for again.
+func NewTransparentCacheWithJanitor(actualPriceService PriceService, maxAge time.Duration, sweepInterval time.Duration) *TransparentCache {
+	c := NewTransparentCache(actualPriceService, maxAge)
+	c.done = make(chan struct{})
+	go c.runJanitor(sweepInterval)
+	return c
+}
+
+func (c *TransparentCache) runJanitor(sweepInterval time.Duration) {
+	ticker := time.NewTicker(sweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.sweep()
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// sweep removes every entry whose lastAssigment is older than maxAge.
+func (c *TransparentCache) sweep() {
+	c.pricesMutex.Lock()
+	defer c.pricesMutex.Unlock()
+	now := time.Now()
+	var stale []string
+	c.store.Range(func(itemCode string, _ float64, ts time.Time) bool {
+		if now.Sub(ts) >= c.maxAge {
+			stale = append(stale, itemCode)
+		}
+		return true
+	})
+	for _, itemCode := range stale {
+		c.store.Delete(itemCode)
+		if elem, ok := c.lruElems[itemCode]; ok {
+			c.lruList.Remove(elem)
+			delete(c.lruElems, itemCode)
+		}
+	}
+}
+
+// Close stops the background janitor goroutine, if one was started via
+// NewTransparentCacheWithJanitor. It is safe to call Close more than once.
+func (c *TransparentCache) Close() error {
+	if c.done != nil {
+		c.closeOnce.Do(func() {
+			close(c.done)
+		})
+	}
+	return nil
+}
+
+// Stop is an alias for Close.
+func (c *TransparentCache) Stop() {
+	c.Close()
 }
 
 // GetPriceFor gets the price for the item, either from the cache or the actual service if it was not cached or too old
-// Is safe for concurrent calls.
+// Is safe for concurrent calls. Concurrent calls for the same itemCode are coalesced into a single
+// call to the actual service, and all callers receive the same result.
 func (c *TransparentCache) GetPriceFor(itemCode string) (float64, error) {
 	c.pricesMutex.Lock()
-	cachePrice, ok := c.prices[itemCode]
-	c.pricesMutex.Unlock()
-	if ok {
-		if time.Since(cachePrice.lastAssigment) < c.maxAge {
-			return cachePrice.value, nil
-		}
-		delete(c.prices, itemCode)
+	value, ts, ok := c.store.Get(itemCode)
+	if ok && time.Since(ts) < c.maxAge {
+		c.touchLocked(itemCode)
+		c.pricesMutex.Unlock()
+		return value, nil
 	}
+	if call, ok := c.inflight[itemCode]; ok {
+		c.pricesMutex.Unlock()
+		call.wg.Wait()
+		return call.value, call.err
+	}
+	call := &inflightCall{}
+	call.wg.Add(1)
+	c.inflight[itemCode] = call
+	c.pricesMutex.Unlock()
+
 	price, err := c.actualPriceService.GetPriceFor(itemCode)
 	if err != nil {
-		return 0, fmt.Errorf("getting price from service : %v", err.Error())
+		err = fmt.Errorf("getting price from service : %v", err.Error())
 	}
+
 	c.pricesMutex.Lock()
-	c.prices[itemCode] = TransparentCachePrice{
-		value:         price,
-		lastAssigment: time.Now(),
+	call.value, call.err = price, err
+	if err == nil {
+		c.insertLocked(itemCode, price, time.Now())
 	}
+	delete(c.inflight, itemCode)
 	c.pricesMutex.Unlock()
-	return price, nil
+
+	call.wg.Done()
+	return price, err
+}
+
+// touchLocked marks itemCode as the most recently used entry. Callers must hold pricesMutex.
+func (c *TransparentCache) touchLocked(itemCode string) {
+	if c.lruList == nil {
+		return
+	}
+	if elem, ok := c.lruElems[itemCode]; ok {
+		c.lruList.MoveToFront(elem)
+	}
+}
+
+// insertLocked stores price under itemCode as of ts and, if capacity is bounded, evicts the
+// least recently used entry as needed to keep the store within maxEntries. Callers must hold pricesMutex.
+func (c *TransparentCache) insertLocked(itemCode string, price float64, ts time.Time) {
+	c.store.Set(itemCode, price, ts)
+	if c.lruList == nil {
+		return
+	}
+	if elem, ok := c.lruElems[itemCode]; ok {
+		c.lruList.MoveToFront(elem)
+	} else {
+		c.lruElems[itemCode] = c.lruList.PushFront(itemCode)
+	}
+	for c.maxEntries > 0 && c.lruList.Len() > c.maxEntries {
+		c.evictOldestLocked()
+	}
+}
+
+// evictOldestLocked removes the least recently used entry and reports it via OnEvict.
+// Callers must hold pricesMutex.
+func (c *TransparentCache) evictOldestLocked() {
+	elem := c.lruList.Back()
+	if elem == nil {
+		return
+	}
+	itemCode := elem.Value.(string)
+	c.lruList.Remove(elem)
+	delete(c.lruElems, itemCode)
+	evictedValue, _, ok := c.store.Get(itemCode)
+	c.store.Delete(itemCode)
+	if ok && c.OnEvict != nil {
+		c.OnEvict(itemCode, evictedValue)
+	}
+}
+
+// Len returns the number of entries currently held in the cache.
+func (c *TransparentCache) Len() int {
+	c.pricesMutex.Lock()
+	defer c.pricesMutex.Unlock()
+	count := 0
+	c.store.Range(func(string, float64, time.Time) bool {
+		count++
+		return true
+	})
+	return count
+}
+
+// Purge removes all entries from the cache.
+func (c *TransparentCache) Purge() {
+	c.pricesMutex.Lock()
+	defer c.pricesMutex.Unlock()
+	var keys []string
+	c.store.Range(func(itemCode string, _ float64, _ time.Time) bool {
+		keys = append(keys, itemCode)
+		return true
+	})
+	for _, itemCode := range keys {
+		c.store.Delete(itemCode)
+	}
+	if c.lruList != nil {
+		c.lruList.Init()
+		c.lruElems = map[string]*list.Element{}
+	}
 }
 
 // GetPricesFor gets the prices for several items at once, some might be found in the cache, others might not
-// If any of the operations returns an error, it should return an error as well
+// The returned slice is ordered the same way as itemCodes. If any of the operations returns an
+// error, it returns that error once every in-flight lookup has wound down; upstream fan-out is
+// bounded by WithMaxConcurrency.
 func (c *TransparentCache) GetPricesFor(itemCodes ...string) ([]float64, error) {
-	results := []float64{}
-	resultsStream, errStream := make(chan float64), make(chan error)
-	for _, itemCode := range itemCodes {
-		go func(internalItemCode string) {
-			price, err := c.GetPriceFor(internalItemCode)
+	results := make([]float64, len(itemCodes))
+
+	maxConcurrency := c.maxConcurrency
+	if maxConcurrency <= 0 || maxConcurrency > len(itemCodes) {
+		maxConcurrency = len(itemCodes)
+	}
+	sem := make(chan struct{}, maxConcurrency)
+
+	g, ctx := errgroup.WithContext(context.Background())
+	for i, itemCode := range itemCodes {
+		i, itemCode := i, itemCode
+		g.Go(func() error {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			defer func() { <-sem }()
+
+			price, err := c.GetPriceFor(itemCode)
 			if err != nil {
-				errStream <- err
-			} else {
-				resultsStream <- price
+				return err
 			}
-		}(itemCode)
+			results[i] = price
+			return nil
+		})
 	}
 
-	for range itemCodes {
-		select {
-		case result := <- resultsStream:
-			results = append(results, result)
-		case err := <- errStream:
-			return []float64{}, err
-		}
+	if err := g.Wait(); err != nil {
+		return nil, err
 	}
 	return results, nil
+}
+
+// cacheEntryGob is the on-disk representation of a single cache entry for SaveFile/LoadFile.
+// It carries the item code next to the value and timestamp so the cache is encoded as a flat slice.
+type cacheEntryGob struct {
+	ItemCode      string
+	Value         float64
+	LastAssigment time.Time
+}
+
+// SaveFile writes the current contents of the cache to path using encoding/gob, so a restart can
+// load them back via LoadFile instead of re-fetching every price from actualPriceService.
+func (c *TransparentCache) SaveFile(path string) error {
+	c.pricesMutex.Lock()
+	entries := []cacheEntryGob{}
+	c.store.Range(func(itemCode string, value float64, ts time.Time) bool {
+		entries = append(entries, cacheEntryGob{ItemCode: itemCode, Value: value, LastAssigment: ts})
+		return true
+	})
+	c.pricesMutex.Unlock()
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating cache file: %v", err.Error())
+	}
+	defer file.Close()
+	if err := gob.NewEncoder(file).Encode(entries); err != nil {
+		return fmt.Errorf("encoding cache file: %v", err.Error())
+	}
+	return nil
+}
+
+// LoadFile reads entries previously written by SaveFile and inserts the ones that have not
+// already expired according to maxAge; stale entries are dropped rather than reloaded.
+func (c *TransparentCache) LoadFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening cache file: %v", err.Error())
+	}
+	defer file.Close()
+
+	var entries []cacheEntryGob
+	if err := gob.NewDecoder(file).Decode(&entries); err != nil {
+		return fmt.Errorf("decoding cache file: %v", err.Error())
+	}
+
+	// Replay oldest-first so the LRU order rebuilt by insertLocked matches the entries' real
+	// recency instead of whatever order Store.Range happened to produce them in.
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].LastAssigment.Before(entries[j].LastAssigment)
+	})
+
+	c.pricesMutex.Lock()
+	defer c.pricesMutex.Unlock()
+	for _, entry := range entries {
+		if time.Since(entry.LastAssigment) >= c.maxAge {
+			continue
+		}
+		c.insertLocked(entry.ItemCode, entry.Value, entry.LastAssigment)
+	}
+	return nil
+}
+
+// Items returns a defensive copy of every entry currently in the cache, keyed by item code, for
+// callers that want to snapshot the cache into their own persistence layer.
+func (c *TransparentCache) Items() map[string]TransparentCachePrice {
+	c.pricesMutex.Lock()
+	defer c.pricesMutex.Unlock()
+	items := map[string]TransparentCachePrice{}
+	c.store.Range(func(itemCode string, value float64, ts time.Time) bool {
+		items[itemCode] = TransparentCachePrice{Value: value, LastAssigment: ts}
+		return true
+	})
+	return items
 }
\ No newline at end of file
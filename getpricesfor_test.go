@@ -0,0 +1,43 @@
+package sample1
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestGetPricesFor_PreservesInputOrder(t *testing.T) {
+	svc := newFakePriceService(map[string]float64{"A": 1, "B": 2, "C": 3})
+	cache := NewTransparentCache(svc, time.Minute, WithMaxConcurrency(1))
+
+	results, err := cache.GetPricesFor("C", "A", "B")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []float64{3, 1, 2}
+	for i, w := range want {
+		if results[i] != w {
+			t.Fatalf("results[%d] = %v, want %v", i, results[i], w)
+		}
+	}
+}
+
+func TestGetPricesFor_ReturnsErrorWithoutLeakingGoroutines(t *testing.T) {
+	svc := newFakePriceService(map[string]float64{"A": 1, "C": 3, "D": 4})
+	svc.errFor = map[string]error{"B": errors.New("boom")}
+	cache := NewTransparentCache(svc, time.Minute)
+
+	before := runtime.NumGoroutine()
+	if _, err := cache.GetPricesFor("A", "B", "C", "D"); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before+2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := runtime.NumGoroutine(); got > before+2 {
+		t.Fatalf("goroutines did not wind down after error: before=%d after=%d", before, got)
+	}
+}
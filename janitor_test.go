@@ -0,0 +1,48 @@
+package sample1
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewTransparentCacheWithJanitor_SweepsExpiredEntries(t *testing.T) {
+	svc := newFakePriceService(map[string]float64{"A": 1})
+	cache := NewTransparentCacheWithJanitor(svc, 20*time.Millisecond, 10*time.Millisecond)
+	defer cache.Close()
+
+	if _, err := cache.GetPriceFor("A"); err != nil {
+		t.Fatal(err)
+	}
+	if got := cache.Len(); got != 1 {
+		t.Fatalf("Len() = %d, want 1", got)
+	}
+
+	deadline := time.Now().Add(500 * time.Millisecond)
+	for cache.Len() != 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := cache.Len(); got != 0 {
+		t.Fatalf("janitor did not sweep expired entry, Len() = %d", got)
+	}
+}
+
+func TestClose_IsSafeToCallMoreThanOnce(t *testing.T) {
+	svc := newFakePriceService(map[string]float64{"A": 1})
+	cache := NewTransparentCacheWithJanitor(svc, time.Minute, 10*time.Millisecond)
+
+	if err := cache.Close(); err != nil {
+		t.Fatalf("first Close: %v", err)
+	}
+	if err := cache.Close(); err != nil {
+		t.Fatalf("second Close: %v", err)
+	}
+}
+
+func TestClose_IsSafeWithoutJanitor(t *testing.T) {
+	svc := newFakePriceService(map[string]float64{"A": 1})
+	cache := NewTransparentCache(svc, time.Minute)
+
+	if err := cache.Close(); err != nil {
+		t.Fatalf("Close on a cache with no janitor: %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package sample1
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTransparentCacheWithCapacity_EvictsLeastRecentlyUsed(t *testing.T) {
+	svc := newFakePriceService(map[string]float64{"A": 1, "B": 2, "C": 3})
+	cache := NewTransparentCacheWithCapacity(svc, time.Minute, 2)
+
+	var evicted []string
+	cache.OnEvict = func(itemCode string, price float64) {
+		evicted = append(evicted, itemCode)
+	}
+
+	mustGet := func(itemCode string) {
+		t.Helper()
+		if _, err := cache.GetPriceFor(itemCode); err != nil {
+			t.Fatalf("GetPriceFor(%q): %v", itemCode, err)
+		}
+	}
+
+	mustGet("A")
+	mustGet("B")
+	mustGet("A") // touch A again, so B becomes the least recently used entry
+	mustGet("C") // should evict B, not A
+
+	if got := cache.Len(); got != 2 {
+		t.Fatalf("Len() = %d, want 2", got)
+	}
+	if len(evicted) != 1 || evicted[0] != "B" {
+		t.Fatalf("evicted = %v, want [B]", evicted)
+	}
+
+	mustGet("A")
+	if got := svc.callCount("A"); got != 1 {
+		t.Fatalf("A should still be cached, actualPriceService called %d times", got)
+	}
+}
+
+func TestPurge_ClearsEntriesAndLRUState(t *testing.T) {
+	svc := newFakePriceService(map[string]float64{"A": 1, "B": 2})
+	cache := NewTransparentCacheWithCapacity(svc, time.Minute, 2)
+
+	if _, err := cache.GetPriceFor("A"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.GetPriceFor("B"); err != nil {
+		t.Fatal(err)
+	}
+
+	cache.Purge()
+	if got := cache.Len(); got != 0 {
+		t.Fatalf("Len() after Purge = %d, want 0", got)
+	}
+
+	if _, err := cache.GetPriceFor("A"); err != nil {
+		t.Fatal(err)
+	}
+	if got := svc.callCount("A"); got != 2 {
+		t.Fatalf("actualPriceService called %d times for A, want 2 (Purge should have dropped the cached entry)", got)
+	}
+}
@@ -0,0 +1,34 @@
+package sample1
+
+import "time"
+
+// inMemoryStore is the default Store implementation, backed by a plain map. It preserves the
+// behavior TransparentCache had before Store was introduced.
+type inMemoryStore struct {
+	entries map[string]TransparentCachePrice
+}
+
+func newInMemoryStore() *inMemoryStore {
+	return &inMemoryStore{entries: map[string]TransparentCachePrice{}}
+}
+
+func (s *inMemoryStore) Get(key string) (float64, time.Time, bool) {
+	entry, ok := s.entries[key]
+	return entry.Value, entry.LastAssigment, ok
+}
+
+func (s *inMemoryStore) Set(key string, value float64, ts time.Time) {
+	s.entries[key] = TransparentCachePrice{Value: value, LastAssigment: ts}
+}
+
+func (s *inMemoryStore) Delete(key string) {
+	delete(s.entries, key)
+}
+
+func (s *inMemoryStore) Range(fn func(key string, value float64, ts time.Time) bool) {
+	for key, entry := range s.entries {
+		if !fn(key, entry.Value, entry.LastAssigment) {
+			return
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package sample1
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSaveFileLoadFile_RoundTrips(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.gob")
+
+	svc := newFakePriceService(map[string]float64{"A": 1, "B": 2})
+	cache := NewTransparentCache(svc, time.Hour)
+	if _, err := cache.GetPriceFor("A"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.GetPriceFor("B"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.SaveFile(path); err != nil {
+		t.Fatalf("SaveFile: %v", err)
+	}
+
+	restored := NewTransparentCache(newFakePriceService(nil), time.Hour)
+	if err := restored.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+
+	items := restored.Items()
+	if len(items) != 2 {
+		t.Fatalf("Items() len = %d, want 2", len(items))
+	}
+	if items["A"].Value != 1 || items["B"].Value != 2 {
+		t.Fatalf("unexpected restored values: %+v", items)
+	}
+}
+
+func TestLoadFile_DropsAlreadyExpiredEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.gob")
+
+	svc := newFakePriceService(map[string]float64{"A": 1})
+	cache := NewTransparentCache(svc, time.Millisecond)
+	if _, err := cache.GetPriceFor("A"); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if err := cache.SaveFile(path); err != nil {
+		t.Fatalf("SaveFile: %v", err)
+	}
+
+	restored := NewTransparentCache(newFakePriceService(nil), time.Millisecond)
+	if err := restored.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	if got := restored.Len(); got != 0 {
+		t.Fatalf("Len() = %d, want 0 (entry should have expired before load)", got)
+	}
+}
+
+func TestLoadFile_RebuildsLRUOrderFromSavedTimestamps(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.gob")
+
+	svc := newFakePriceService(map[string]float64{"A": 1, "B": 2})
+	cache := NewTransparentCache(svc, time.Hour)
+	if _, err := cache.GetPriceFor("A"); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if _, err := cache.GetPriceFor("B"); err != nil {
+		t.Fatal(err) // B is the more recently used entry
+	}
+	if err := cache.SaveFile(path); err != nil {
+		t.Fatalf("SaveFile: %v", err)
+	}
+
+	restoredSvc := newFakePriceService(map[string]float64{"C": 3})
+	restored := NewTransparentCacheWithCapacity(restoredSvc, time.Hour, 2)
+	if err := restored.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+
+	var evicted string
+	restored.OnEvict = func(itemCode string, price float64) {
+		evicted = itemCode
+	}
+	if _, err := restored.GetPriceFor("C"); err != nil {
+		t.Fatal(err)
+	}
+	if evicted != "A" {
+		t.Fatalf("evicted = %q, want %q (A was the least recently used entry before saving)", evicted, "A")
+	}
+}
@@ -0,0 +1,88 @@
+package sample1
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// RedisStore is a Store backed by Redis, so a cache's entries can be shared across processes
+// instead of being confined to a single process's memory.
+type RedisStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisStore builds a Store backed by client. Keys are namespaced under prefix to avoid
+// colliding with unrelated data kept in the same Redis instance.
+func NewRedisStore(client *redis.Client, prefix string) *RedisStore {
+	return &RedisStore{client: client, prefix: prefix}
+}
+
+func (s *RedisStore) redisKey(key string) string {
+	return s.prefix + key
+}
+
+func (s *RedisStore) Get(key string) (float64, time.Time, bool) {
+	raw, err := s.client.Get(context.Background(), s.redisKey(key)).Result()
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+	value, ts, err := decodeRedisEntry(raw)
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+	return value, ts, true
+}
+
+func (s *RedisStore) Set(key string, value float64, ts time.Time) {
+	s.client.Set(context.Background(), s.redisKey(key), encodeRedisEntry(value, ts), 0)
+}
+
+func (s *RedisStore) Delete(key string) {
+	s.client.Del(context.Background(), s.redisKey(key))
+}
+
+func (s *RedisStore) Range(fn func(key string, value float64, ts time.Time) bool) {
+	ctx := context.Background()
+	iter := s.client.Scan(ctx, 0, s.prefix+"*", 0).Iterator()
+	for iter.Next(ctx) {
+		raw, err := s.client.Get(ctx, iter.Val()).Result()
+		if err != nil {
+			continue
+		}
+		value, ts, err := decodeRedisEntry(raw)
+		if err != nil {
+			continue
+		}
+		itemCode := strings.TrimPrefix(iter.Val(), s.prefix)
+		if !fn(itemCode, value, ts) {
+			return
+		}
+	}
+}
+
+// encodeRedisEntry packs a price and its assignment time into the string stored in Redis.
+func encodeRedisEntry(value float64, ts time.Time) string {
+	return fmt.Sprintf("%s|%d", strconv.FormatFloat(value, 'f', -1, 64), ts.UnixNano())
+}
+
+func decodeRedisEntry(raw string) (float64, time.Time, error) {
+	parts := strings.SplitN(raw, "|", 2)
+	if len(parts) != 2 {
+		return 0, time.Time{}, fmt.Errorf("malformed cache entry %q", raw)
+	}
+	value, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	nanos, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	return value, time.Unix(0, nanos), nil
+}
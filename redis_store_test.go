@@ -0,0 +1,28 @@
+package sample1
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEncodeDecodeRedisEntry_RoundTrips(t *testing.T) {
+	ts := time.Unix(1700000000, 123456000)
+	raw := encodeRedisEntry(42.5, ts)
+
+	value, decodedTs, err := decodeRedisEntry(raw)
+	if err != nil {
+		t.Fatalf("decodeRedisEntry: %v", err)
+	}
+	if value != 42.5 {
+		t.Fatalf("value = %v, want 42.5", value)
+	}
+	if !decodedTs.Equal(ts) {
+		t.Fatalf("ts = %v, want %v", decodedTs, ts)
+	}
+}
+
+func TestDecodeRedisEntry_RejectsMalformedInput(t *testing.T) {
+	if _, _, err := decodeRedisEntry("not-a-valid-entry"); err == nil {
+		t.Fatal("expected an error for malformed input")
+	}
+}
@@ -0,0 +1,39 @@
+package sample1
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGetPriceFor_CoalescesConcurrentMisses(t *testing.T) {
+	svc := newFakePriceService(map[string]float64{"A": 10})
+	svc.delay = 50 * time.Millisecond
+	cache := NewTransparentCache(svc, time.Minute)
+
+	const n = 20
+	var wg sync.WaitGroup
+	results := make([]float64, n)
+	errs := make([]error, n)
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		i := i
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = cache.GetPriceFor("A")
+		}()
+	}
+	wg.Wait()
+
+	for i := range results {
+		if errs[i] != nil {
+			t.Fatalf("call %d: unexpected error: %v", i, errs[i])
+		}
+		if results[i] != 10 {
+			t.Fatalf("call %d: price = %v, want 10", i, results[i])
+		}
+	}
+	if got := svc.callCount("A"); got != 1 {
+		t.Fatalf("actualPriceService called %d times, want 1", got)
+	}
+}
@@ -0,0 +1,21 @@
+package sample1
+
+import "time"
+
+// Store is the pluggable backing store for a TransparentCache's price entries. It lets the
+// cache be backed by plain process memory, a shared store such as Redis, or anything else that
+// can hold a value and the time it was assigned, keyed by itemCode.
+//
+// TransparentCache always calls into the Store while holding its own pricesMutex, so an
+// implementation does not need to do its own locking unless it is also used concurrently outside
+// of a TransparentCache.
+type Store interface {
+	// Get returns the value stored under key, the time it was assigned, and whether it was found.
+	Get(key string) (value float64, ts time.Time, ok bool)
+	// Set stores value under key along with the time it was assigned.
+	Set(key string, value float64, ts time.Time)
+	// Delete removes key from the store, if present.
+	Delete(key string)
+	// Range calls fn for every entry in the store, stopping early if fn returns false.
+	Range(fn func(key string, value float64, ts time.Time) bool)
+}
@@ -0,0 +1,79 @@
+package sample1
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingStore is a minimal Store implementation independent of inMemoryStore, used to prove
+// that TransparentCache only talks to its backing store through the Store interface.
+type recordingStore struct {
+	mu      sync.Mutex
+	entries map[string]TransparentCachePrice
+	sets    int
+}
+
+func newRecordingStore() *recordingStore {
+	return &recordingStore{entries: map[string]TransparentCachePrice{}}
+}
+
+func (s *recordingStore) Get(key string) (float64, time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.entries[key]
+	return entry.Value, entry.LastAssigment, ok
+}
+
+func (s *recordingStore) Set(key string, value float64, ts time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sets++
+	s.entries[key] = TransparentCachePrice{Value: value, LastAssigment: ts}
+}
+
+func (s *recordingStore) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+}
+
+func (s *recordingStore) Range(fn func(key string, value float64, ts time.Time) bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, entry := range s.entries {
+		if !fn(key, entry.Value, entry.LastAssigment) {
+			return
+		}
+	}
+}
+
+func TestNewTransparentCacheWithStore_PreservesTTLThroughCustomStore(t *testing.T) {
+	svc := newFakePriceService(map[string]float64{"A": 1})
+	store := newRecordingStore()
+	cache := NewTransparentCacheWithStore(svc, 30*time.Millisecond, store)
+
+	price, err := cache.GetPriceFor("A")
+	if err != nil || price != 1 {
+		t.Fatalf("GetPriceFor = %v, %v, want 1, nil", price, err)
+	}
+	if store.sets != 1 {
+		t.Fatalf("store.sets = %d, want 1", store.sets)
+	}
+
+	// Still fresh: should be served from the custom store, not the upstream service.
+	if _, err := cache.GetPriceFor("A"); err != nil {
+		t.Fatal(err)
+	}
+	if got := svc.callCount("A"); got != 1 {
+		t.Fatalf("actualPriceService called %d times while entry was fresh, want 1", got)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if _, err := cache.GetPriceFor("A"); err != nil {
+		t.Fatal(err)
+	}
+	if got := svc.callCount("A"); got != 2 {
+		t.Fatalf("actualPriceService called %d times after expiry, want 2", got)
+	}
+}
@@ -0,0 +1,43 @@
+package sample1
+
+import (
+	"sync"
+	"time"
+)
+
+// fakePriceService is a test double for PriceService that counts calls per itemCode and can be
+// configured with a fixed delay and per-itemCode errors.
+type fakePriceService struct {
+	mu     sync.Mutex
+	prices map[string]float64
+	errFor map[string]error
+	delay  time.Duration
+	calls  map[string]int
+}
+
+func newFakePriceService(prices map[string]float64) *fakePriceService {
+	return &fakePriceService{prices: prices, calls: map[string]int{}}
+}
+
+func (f *fakePriceService) GetPriceFor(itemCode string) (float64, error) {
+	f.mu.Lock()
+	f.calls[itemCode]++
+	delay := f.delay
+	err := f.errFor[itemCode]
+	price := f.prices[itemCode]
+	f.mu.Unlock()
+
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return price, nil
+}
+
+func (f *fakePriceService) callCount(itemCode string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[itemCode]
+}